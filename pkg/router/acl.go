@@ -0,0 +1,212 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandPolicy describes where and by whom a command may be invoked.
+// It is attached to a command at registration time via RegisterCommandWithPolicy.
+type CommandPolicy struct {
+	RequiredPerms   int
+	AllowedChannels []string
+	DeniedChannels  []string
+	AllowedRoles    []string
+	DeniedRoles     []string
+	RequireDM       bool
+	OwnerOnly       bool
+}
+
+// CommandACL holds the per-guild, runtime-editable allow/deny lists for a
+// single command. These are layered on top of the static CommandPolicy set
+// at registration time and are persisted per-guild in ServerSettings.
+type CommandACL struct {
+	AllowedChannels []string
+	DeniedChannels  []string
+	AllowedRoles    []string
+	DeniedRoles     []string
+}
+
+// RegisterCommandWithPolicy behaves like RegisterCommand but additionally
+// attaches a CommandPolicy that HandleCommand consults before dispatching.
+func (c *CommandRouter) RegisterCommandWithPolicy(command string, help string, f MessageHandler, policy CommandPolicy) error {
+	return c.RegisterCommandInfo(CommandInfo{
+		Command: command,
+		Help:    help,
+		Policy:  policy,
+		Handler: adaptMessageHandler(f),
+	})
+}
+
+// RegisterOwner designates userID as the bot owner for OwnerOnly commands.
+func (c *CommandRouter) RegisterOwner(userID string) {
+	c.ownerID = userID
+}
+
+// checkPolicy returns true if ctx's invoker is allowed to invoke command
+// under its registered CommandPolicy and the guild's persisted CommandACL,
+// replying with a refusal message when the check fails (OwnerOnly refusals
+// are silent). It works the same for message- and interaction-backed ctx
+// values.
+func (c *CommandRouter) checkPolicy(ctx *Ctx, command string) bool {
+	policy, ok := c.policies[command]
+	if !ok {
+		return true
+	}
+	return c.checkPolicyFor(ctx, command, policy)
+}
+
+// checkPolicyFor runs the same checks as checkPolicy against an explicit
+// CommandPolicy. Unlike checkPolicy it runs the checks even for a command
+// with no entry in c.policies: HandleCommand passes a RegisterCommandEx
+// spec's own Policy, and HandleInteraction passes c.policies[name], which
+// is the zero CommandPolicy (guild CommandACL still applies) for slash
+// commands registered without one.
+func (c *CommandRouter) checkPolicyFor(ctx *Ctx, command string, policy CommandPolicy) bool {
+	if policy.OwnerOnly && ctx.User().ID != c.ownerID {
+		return false
+	}
+
+	if policy.RequireDM && ctx.GuildID() != "" {
+		ctx.Replyf("%s can only be used in a DM", command)
+		return false
+	}
+
+	if policy.RequiredPerms != 0 {
+		if ok, _ := MemberHasPermission(ctx.Session, ctx.GuildID(), ctx.User().ID, policy.RequiredPerms); !ok {
+			ctx.Reply("You don't have permission to use that command")
+			return false
+		}
+	}
+
+	acl := c.commandACL(ctx.GuildID(), command)
+
+	if !channelAllowed(ctx.ChannelID(), policy.AllowedChannels, acl.AllowedChannels, policy.DeniedChannels, acl.DeniedChannels) {
+		ctx.Replyf("%s can't be used in this channel", command)
+		return false
+	}
+
+	if !c.rolesAllowed(ctx, policy.AllowedRoles, acl.AllowedRoles, policy.DeniedRoles, acl.DeniedRoles) {
+		ctx.Reply("You don't have the required role to use that command")
+		return false
+	}
+
+	return true
+}
+
+// commandACL returns the persisted CommandACL for command in guildID,
+// or a zero-value CommandACL if none has been set yet.
+func (c *CommandRouter) commandACL(guildID string, command string) CommandACL {
+	server, ok := c.Servers[guildID]
+	if !ok || server.CommandACLs == nil {
+		return CommandACL{}
+	}
+	return server.CommandACLs[command]
+}
+
+func channelAllowed(channelID string, policyAllowed, aclAllowed, policyDenied, aclDenied []string) bool {
+	if contains(policyDenied, channelID) || contains(aclDenied, channelID) {
+		return false
+	}
+	if len(policyAllowed) == 0 && len(aclAllowed) == 0 {
+		return true
+	}
+	return contains(policyAllowed, channelID) || contains(aclAllowed, channelID)
+}
+
+func (c *CommandRouter) rolesAllowed(ctx *Ctx, policyAllowed, aclAllowed, policyDenied, aclDenied []string) bool {
+	if len(policyAllowed) == 0 && len(aclAllowed) == 0 && len(policyDenied) == 0 && len(aclDenied) == 0 {
+		return true
+	}
+
+	member, err := ctx.Member()
+	if err != nil {
+		return false
+	}
+
+	for _, roleID := range member.Roles {
+		if contains(policyDenied, roleID) || contains(aclDenied, roleID) {
+			return false
+		}
+	}
+
+	if len(policyAllowed) == 0 && len(aclAllowed) == 0 {
+		return true
+	}
+
+	for _, roleID := range member.Roles {
+		if contains(policyAllowed, roleID) || contains(aclAllowed, roleID) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, val string) bool {
+	for _, v := range list {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow adds a channel or role ID to a command's allow-list for the invoking
+// guild. It is registered as the "!allow" admin command.
+func (c *CommandRouter) Allow(s *discordgo.Session, m *discordgo.MessageCreate) {
+	c.editACL(s, m, true)
+}
+
+// Deny adds a channel or role ID to a command's deny-list for the invoking
+// guild. It is registered as the "!deny" admin command.
+func (c *CommandRouter) Deny(s *discordgo.Session, m *discordgo.MessageCreate) {
+	c.editACL(s, m, false)
+}
+
+func (c *CommandRouter) editACL(s *discordgo.Session, m *discordgo.MessageCreate, allow bool) {
+	if ok, _ := MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator); !ok {
+		return
+	}
+
+	args := strings.Fields(m.Content)
+	if len(args) < 3 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: !allow|!deny <command> <channel|role> <id>")
+		return
+	}
+	command, kind, id := args[0], args[1], args[2]
+
+	server, ok := c.Servers[m.GuildID]
+	if !ok {
+		server = ServerSettings{Prefix: c.DefaultPrefix, GuildID: m.GuildID}
+	}
+	if server.CommandACLs == nil {
+		server.CommandACLs = make(map[string]CommandACL)
+	}
+	acl := server.CommandACLs[command]
+
+	switch {
+	case allow && kind == "channel":
+		acl.AllowedChannels = append(acl.AllowedChannels, id)
+	case allow && kind == "role":
+		acl.AllowedRoles = append(acl.AllowedRoles, id)
+	case !allow && kind == "channel":
+		acl.DeniedChannels = append(acl.DeniedChannels, id)
+	case !allow && kind == "role":
+		acl.DeniedRoles = append(acl.DeniedRoles, id)
+	default:
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Unknown target type %q, expected \"channel\" or \"role\"", kind))
+		return
+	}
+
+	server.CommandACLs[command] = acl
+	c.Servers[m.GuildID] = server
+
+	if err := c.store.Save(server); err != nil {
+		fmt.Println("Error", err)
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "ACL updated")
+}
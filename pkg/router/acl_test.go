@@ -0,0 +1,83 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestContains(t *testing.T) {
+	list := []string{"a", "b", "c"}
+	if !contains(list, "b") {
+		t.Error("expected list to contain \"b\"")
+	}
+	if contains(list, "z") {
+		t.Error("expected list not to contain \"z\"")
+	}
+	if contains(nil, "a") {
+		t.Error("expected a nil list to contain nothing")
+	}
+}
+
+func TestChannelAllowed(t *testing.T) {
+	cases := []struct {
+		name                                               string
+		channelID                                          string
+		policyAllowed, aclAllowed, policyDenied, aclDenied []string
+		want                                               bool
+	}{
+		{name: "no restrictions allows everything", channelID: "c1", want: true},
+		{name: "policy deny wins over no allow list", channelID: "c1", policyDenied: []string{"c1"}, want: false},
+		{name: "acl deny wins over no allow list", channelID: "c1", aclDenied: []string{"c1"}, want: false},
+		{name: "policy allow list excludes other channels", channelID: "c2", policyAllowed: []string{"c1"}, want: false},
+		{name: "policy allow list includes listed channel", channelID: "c1", policyAllowed: []string{"c1"}, want: true},
+		{name: "acl allow list includes listed channel", channelID: "c1", aclAllowed: []string{"c1"}, want: true},
+		{name: "deny takes priority over allow", channelID: "c1", policyAllowed: []string{"c1"}, policyDenied: []string{"c1"}, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := channelAllowed(tc.channelID, tc.policyAllowed, tc.aclAllowed, tc.policyDenied, tc.aclDenied)
+			if got != tc.want {
+				t.Errorf("channelAllowed(%q) = %v, want %v", tc.channelID, got, tc.want)
+			}
+		})
+	}
+}
+
+func interactionCtxWithRoles(roles []string) *Ctx {
+	return &Ctx{
+		Interaction: &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				GuildID:   "g1",
+				ChannelID: "c1",
+				Member: &discordgo.Member{
+					User:  &discordgo.User{ID: "u1"},
+					Roles: roles,
+				},
+			},
+		},
+	}
+}
+
+func TestRolesAllowed(t *testing.T) {
+	c := &CommandRouter{}
+
+	if !c.rolesAllowed(interactionCtxWithRoles(nil), nil, nil, nil, nil) {
+		t.Error("expected no restrictions to allow a member with no roles")
+	}
+
+	ctx := interactionCtxWithRoles([]string{"mod"})
+	if !c.rolesAllowed(ctx, []string{"mod"}, nil, nil, nil) {
+		t.Error("expected a member with an allowed role to pass")
+	}
+	if c.rolesAllowed(ctx, []string{"admin"}, nil, nil, nil) {
+		t.Error("expected a member without any allowed role to fail")
+	}
+	if c.rolesAllowed(ctx, nil, nil, []string{"mod"}, nil) {
+		t.Error("expected a denied role to fail even with no allow list")
+	}
+	if c.rolesAllowed(ctx, []string{"mod"}, nil, []string{"mod"}, nil) {
+		t.Error("expected deny to take priority over allow")
+	}
+}
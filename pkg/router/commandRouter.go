@@ -1,29 +1,52 @@
 package router
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/sdomino/scribble"
 	log "github.com/sirupsen/logrus"
 )
 
 type MessageHandler func(*discordgo.Session, *discordgo.MessageCreate)
 
-//CommandRouter Handles routing of chat commands to handler functions
+// CommandRouter Handles routing of chat commands to handler functions
 type CommandRouter struct {
-	commands      map[string]MessageHandler
-	helpText      map[string]string
+	commands       map[string]HandlerFunc
+	helpText       map[string]string
+	commandInfo    map[string]CommandInfo
+	aliases        map[string]string
+	policies       map[string]CommandPolicy
+	cooldownConfig map[string]cooldownSpec
+	cooldowns      map[cooldownKey]time.Time
+	cooldownMu     sync.Mutex
+	cooldownChecks uint64
+	globalCooldown time.Duration
+	exSpecs        []CommandSpec
+	slashHandlers  map[string]InteractionHandler
+	slashCommands  []*discordgo.ApplicationCommand
+	ownerID        string
+	DefaultPrefix  string
+	Servers        map[string]ServerSettings
+	store          SettingsStore
+}
+
+// Options configures NewCommandRouterWithOptions.
+type Options struct {
+	// Store persists ServerSettings. Defaults to a ScribbleStore rooted at
+	// ./settings when nil, matching the router's original behavior.
+	Store SettingsStore
+	// DefaultPrefix is the router's trigger prefix for guilds that haven't
+	// set their own via SetPrefix. Defaults to "!" when empty.
 	DefaultPrefix string
-	Servers       map[string]ServerSettings
-	db            *scribble.Driver
 }
 
 type ServerSettings struct {
-	Prefix  string
-	GuildID string
+	Prefix      string
+	GuildID     string
+	CommandACLs map[string]CommandACL
 }
 
 // NewCommandRouter Sets up a new command router.
@@ -31,50 +54,77 @@ func NewCommandRouter() *CommandRouter {
 	return NewCommandRouterWithPrefix("!")
 }
 
+// NewCommandRouterWithPrefix sets up a new command router backed by the
+// default ScribbleStore, rooted at ./settings.
 func NewCommandRouterWithPrefix(prefix string) *CommandRouter {
-	dir := "./settings"
+	return NewCommandRouterWithOptions(Options{DefaultPrefix: prefix})
+}
 
-	db, err := scribble.New(dir, nil)
-	if err != nil {
-		fmt.Println("Error", err)
+// NewCommandRouterWithOptions sets up a new command router using opts.Store
+// for persistence (a ScribbleStore rooted at ./settings when nil) and
+// opts.DefaultPrefix as the trigger prefix (defaulting to "!").
+func NewCommandRouterWithOptions(opts Options) *CommandRouter {
+	store := opts.Store
+	if store == nil {
+		s, err := NewScribbleStore("./settings")
+		if err != nil {
+			fmt.Println("Error", err)
+		}
+		store = s
 	}
 
-	//Load Pricedb
-	servers, err := db.ReadAll("Servers")
+	servers, err := store.LoadAll()
 	if err != nil {
 		fmt.Println("Error", err)
 	}
 
 	router := &CommandRouter{
-		commands: make(map[string]MessageHandler),
-		helpText: make(map[string]string),
-		Servers:  make(map[string]ServerSettings),
-		db:       db,
+		commands:       make(map[string]HandlerFunc),
+		helpText:       make(map[string]string),
+		commandInfo:    make(map[string]CommandInfo),
+		aliases:        make(map[string]string),
+		policies:       make(map[string]CommandPolicy),
+		cooldownConfig: make(map[string]cooldownSpec),
+		cooldowns:      make(map[cooldownKey]time.Time),
+		slashHandlers:  make(map[string]InteractionHandler),
+		Servers:        make(map[string]ServerSettings),
+		store:          store,
 	}
 
 	//AddServerPrefixes to the Router
-	for _, prefix := range servers {
-		var serverSettings ServerSettings
-		if err := json.Unmarshal(prefix, &serverSettings); err != nil {
-			fmt.Println("Error", err)
-		}
+	for _, serverSettings := range servers {
 		router.Servers[serverSettings.GuildID] = serverSettings
 	}
 
-	router.DefaultPrefix = "!"
-	router.RegisterCommand("help", "This help text", router.help)
+	router.DefaultPrefix = opts.DefaultPrefix
+	if router.DefaultPrefix == "" {
+		router.DefaultPrefix = "!"
+	}
+	router.RegisterCommandInfo(CommandInfo{
+		Command:  "help",
+		Help:     "Lists available commands, or shows detailed usage for a single command",
+		Category: "General",
+		Usage:    "help [command]",
+		Handler:  router.help,
+	})
+	router.RegisterCommandInfo(CommandInfo{
+		Command:  "allow",
+		Help:     "Allow a channel or role to use a command",
+		Category: "Admin",
+		Usage:    "allow <command> <channel|role> <id>",
+		Handler:  adaptMessageHandler(router.Allow),
+	})
+	router.RegisterCommandInfo(CommandInfo{
+		Command:  "deny",
+		Help:     "Deny a channel or role from using a command",
+		Category: "Admin",
+		Usage:    "deny <command> <channel|role> <id>",
+		Handler:  adaptMessageHandler(router.Deny),
+	})
 
 	return router
 }
 
-func (c *CommandRouter) help(s *discordgo.Session, m *discordgo.MessageCreate) {
-	var helpMessage = "Here's a list of available commands:\n"
-	for key, value := range c.helpText {
-		helpMessage += fmt.Sprintf("* %s: %s\n", key, value)
-	}
-	s.ChannelMessageSend(m.ChannelID, helpMessage)
-}
-
 // SetPrefix Set the bot's trigger prefix to message string, not included by default in the command list, make sure to register
 func (c *CommandRouter) SetPrefix(s *discordgo.Session, m *discordgo.MessageCreate) {
 	if ok, _ := MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator); ok {
@@ -91,7 +141,7 @@ func (c *CommandRouter) SetPrefix(s *discordgo.Session, m *discordgo.MessageCrea
 		}
 
 		server.Prefix = m.Content
-		if err := c.db.Write("Servers", server.GuildID, server); err != nil {
+		if err := c.store.Save(server); err != nil {
 			fmt.Println("Error", err)
 		}
 
@@ -105,17 +155,33 @@ func (c *CommandRouter) SetPrefix(s *discordgo.Session, m *discordgo.MessageCrea
 }
 
 // RegisterCommand Adds a command, it's help text, and function to the router. the help command is reserved
+//
+// Deprecated: kept as a backwards-compatible shim around RegisterCommandFunc
+// for handlers that don't need a Ctx or error reporting. Prefer
+// RegisterCommandFunc for new commands.
 func (c *CommandRouter) RegisterCommand(command string, help string, f MessageHandler) error {
-	if _, ok := c.commands[command]; ok {
-		return fmt.Errorf("Command %s is already registered", command)
-	}
-	if _, ok := c.helpText[command]; ok {
-		return fmt.Errorf("Help for command %s is already registered", command)
-	}
+	return c.RegisterCommandFunc(command, help, adaptMessageHandler(f))
+}
+
+// RegisterCommandFunc adds a command, its help text, and a HandlerFunc to
+// the router. The help command is reserved.
+func (c *CommandRouter) RegisterCommandFunc(command string, help string, f HandlerFunc) error {
+	return c.RegisterCommandInfo(CommandInfo{Command: command, Help: help, Handler: f})
+}
 
-	c.commands[command] = f
-	c.helpText[command] = help
-	return nil
+// dispatch invokes f with ctx, logging and surfacing any returned error to
+// the user as a ❌ reaction plus an error message.
+func (c *CommandRouter) dispatch(label string, ctx *Ctx, f HandlerFunc) {
+	if err := f(ctx); err != nil {
+		log.WithFields(log.Fields{
+			"command": label,
+			"guild":   ctx.GuildID(),
+			"channel": ctx.ChannelID(),
+			"user":    ctx.User().ID,
+		}).WithError(err).Error("command handler returned an error")
+		ctx.ReactError()
+		ctx.Replyf("Something went wrong running that command: %s", err)
+	}
 }
 
 // HandleCommand Takes Discord input and tries to find a relevant command, can be passed to discord-go's AddHandler
@@ -136,20 +202,57 @@ func (c *CommandRouter) HandleCommand(s *discordgo.Session, m *discordgo.Message
 		m.Content = m.Content[len(prefixToCheck):]
 		args := strings.Split(m.Content, " ")
 
+		// Resolve aliases to their canonical command name before lookup
+		command := args[0]
+		if canonical, ok := c.aliases[command]; ok {
+			command = canonical
+		}
+
 		// Check if command is registered
-		if f, ok := c.commands[args[0]]; ok {
+		if f, ok := c.commands[command]; ok {
 			// Remove command from content, trim spaces
 			m.Content = strings.TrimSpace(m.Content[len(args[0]):])
+			ctx := newCtx(s, m, nil)
+			if !c.checkPolicy(ctx, command) {
+				log.Debugf("Refusing %s for %s: policy check failed", command, m.Author.ID)
+				return
+			}
+			if ok, remaining := c.checkCooldown(command, ctx); !ok {
+				ctx.Reply(cooldownMessage(remaining))
+				return
+			}
 			// Call function
-			log.Debugf("Calling handler for command: %s", args[0])
-			f(s, m)
+			log.Debugf("Calling handler for command: %s", command)
+			c.dispatch(command, ctx, f)
 			return
-		} else if len(args[0]) == 0 && len(prefixToCheck) == 0 {
+		}
+
+		// Fast path missed, try the ordered non-exact specs in registration order
+		for _, spec := range c.exSpecs {
+			matched, groups := spec.match(m.Content)
+			if !matched {
+				continue
+			}
+			ctx := newCtx(s, m, groups)
+			if !c.checkPolicyFor(ctx, spec.label(), spec.Policy) {
+				log.Debugf("Refusing %s for %s: policy check failed", spec.label(), m.Author.ID)
+				return
+			}
+			if ok, remaining := c.checkCooldown(spec.label(), ctx); !ok {
+				ctx.Reply(cooldownMessage(remaining))
+				return
+			}
+			log.Debugf("Calling handler for spec: %s", spec.label())
+			c.dispatch(spec.label(), ctx, spec.Handler)
+			return
+		}
+
+		if len(args[0]) == 0 && len(prefixToCheck) == 0 {
 			s.ChannelMessageSend(m.ChannelID, "Sub command needed. ")
 		} else if len(args[0]) > 0 {
 			s.ChannelMessageSend(m.ChannelID, "Command not recognized")
 		}
-		c.help(s, m)
+		c.dispatch("help", &Ctx{Session: s, Message: m}, c.help)
 	}
 }
 
@@ -160,10 +263,11 @@ func (c *CommandRouter) HandleCommand(s *discordgo.Session, m *discordgo.Message
 // If you want to check for multiple permissions you would use the bitwise OR
 // operator to pack more bits in. (e.g): PermissionAdministrator|PermissionAddReactions
 // =================================================================================
-//     s          :  discordgo session
-//     guildID    :  guildID of the member you wish to check the roles of
-//     userID     :  userID of the member you wish to retrieve
-//     permission :  the permission you wish to check for
+//
+//	s          :  discordgo session
+//	guildID    :  guildID of the member you wish to check the roles of
+//	userID     :  userID of the member you wish to retrieve
+//	permission :  the permission you wish to check for
 func MemberHasPermission(s *discordgo.Session, guildID string, userID string, permission int) (bool, error) {
 	member, err := s.State.Member(guildID, userID)
 	if err != nil {
@@ -179,7 +283,7 @@ func MemberHasPermission(s *discordgo.Session, guildID string, userID string, pe
 		if err != nil {
 			return false, err
 		}
-		if role.Permissions&permission != 0 {
+		if role.Permissions&int64(permission) != 0 {
 			return true, nil
 		}
 	}
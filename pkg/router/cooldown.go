@@ -0,0 +1,132 @@
+package router
+
+import (
+	"fmt"
+	"time"
+)
+
+// CooldownScope controls which identifier a command's cooldown is keyed on.
+type CooldownScope int
+
+const (
+	// CooldownScopeUser keys the cooldown per invoking user.
+	CooldownScopeUser CooldownScope = iota
+	// CooldownScopeChannel keys the cooldown per channel.
+	CooldownScopeChannel
+	// CooldownScopeGuild keys the cooldown per guild.
+	CooldownScopeGuild
+	// CooldownScopeGlobal applies a single cooldown to every invocation.
+	CooldownScopeGlobal
+)
+
+// cooldownSpec is the per-command cooldown configuration set at
+// registration time via CommandInfo.Cooldown/CooldownScope or
+// CommandSpec.Cooldown/CooldownScope.
+type cooldownSpec struct {
+	Duration time.Duration
+	Scope    CooldownScope
+}
+
+// cooldownKey identifies one (command, scope, invoker) cooldown entry.
+type cooldownKey struct {
+	command string
+	scope   CooldownScope
+	id      string
+}
+
+// cooldownSweepInterval bounds how often checkCooldown scans the full
+// cooldowns map for expired entries. Sweeping on every call would make
+// every check O(n) in the number of distinct cooldown keys ever seen;
+// sweeping every cooldownSweepInterval calls instead amortizes that cost
+// while still bounding memory for popular per-user/per-channel/per-guild
+// cooldowns.
+const cooldownSweepInterval = 100
+
+// SetGlobalCooldown sets the cooldown duration applied to any command that
+// doesn't declare its own via CommandInfo.Cooldown/CommandSpec.Cooldown.
+func (c *CommandRouter) SetGlobalCooldown(d time.Duration) {
+	c.globalCooldown = d
+}
+
+// ResetCooldown clears any recorded cooldown for command under id, across
+// every CooldownScope, letting admins lift a cooldown early.
+func (c *CommandRouter) ResetCooldown(command string, id string) {
+	c.cooldownMu.Lock()
+	defer c.cooldownMu.Unlock()
+
+	delete(c.cooldowns, cooldownKey{command: command, scope: CooldownScopeUser, id: id})
+	delete(c.cooldowns, cooldownKey{command: command, scope: CooldownScopeChannel, id: id})
+	delete(c.cooldowns, cooldownKey{command: command, scope: CooldownScopeGuild, id: id})
+	delete(c.cooldowns, cooldownKey{command: command, scope: CooldownScopeGlobal, id: ""})
+}
+
+// checkCooldown reports whether command may run for ctx's invoker, stamping
+// the invocation time when it's allowed. When it isn't, it returns the
+// remaining wait. Expired entries are purged in periodic sweeps every
+// cooldownSweepInterval calls (see sweepCooldownsLocked) rather than on
+// every access, so the map doesn't grow without bound for commands with a
+// per-user/per-channel/per-guild cooldown that many distinct invokers
+// trigger once and never again.
+func (c *CommandRouter) checkCooldown(command string, ctx *Ctx) (bool, time.Duration) {
+	spec := c.cooldownConfig[command]
+	duration := spec.Duration
+	if duration <= 0 {
+		duration = c.globalCooldown
+	}
+	if duration <= 0 {
+		return true, 0
+	}
+
+	key := cooldownKey{command: command, scope: spec.Scope, id: cooldownScopeID(spec.Scope, ctx)}
+
+	c.cooldownMu.Lock()
+	defer c.cooldownMu.Unlock()
+
+	c.sweepCooldownsLocked()
+
+	if last, ok := c.cooldowns[key]; ok {
+		if elapsed := time.Since(last); elapsed < duration {
+			return false, duration - elapsed
+		}
+	}
+	c.cooldowns[key] = time.Now()
+	return true, 0
+}
+
+// sweepCooldownsLocked deletes every cooldown entry whose duration has
+// elapsed, but only once every cooldownSweepInterval calls. c.cooldownMu
+// must already be held.
+func (c *CommandRouter) sweepCooldownsLocked() {
+	c.cooldownChecks++
+	if c.cooldownChecks%cooldownSweepInterval != 0 {
+		return
+	}
+
+	now := time.Now()
+	for key, last := range c.cooldowns {
+		duration := c.cooldownConfig[key.command].Duration
+		if duration <= 0 {
+			duration = c.globalCooldown
+		}
+		if duration <= 0 || now.Sub(last) >= duration {
+			delete(c.cooldowns, key)
+		}
+	}
+}
+
+func cooldownScopeID(scope CooldownScope, ctx *Ctx) string {
+	switch scope {
+	case CooldownScopeChannel:
+		return ctx.ChannelID()
+	case CooldownScopeGuild:
+		return ctx.GuildID()
+	case CooldownScopeGlobal:
+		return ""
+	default:
+		return ctx.User().ID
+	}
+}
+
+func cooldownMessage(remaining time.Duration) string {
+	return fmt.Sprintf("⏳ try again in %ds", int(remaining.Round(time.Second).Seconds()))
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func testCtx(userID, channelID, guildID string) *Ctx {
+	return &Ctx{
+		Message: &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				Author:    &discordgo.User{ID: userID},
+				ChannelID: channelID,
+				GuildID:   guildID,
+			},
+		},
+	}
+}
+
+func newTestRouter() *CommandRouter {
+	return &CommandRouter{
+		cooldowns:      make(map[cooldownKey]time.Time),
+		cooldownConfig: make(map[string]cooldownSpec),
+	}
+}
+
+func TestCheckCooldownAllowsFirstInvocationThenBlocks(t *testing.T) {
+	c := newTestRouter()
+	c.cooldownConfig["ping"] = cooldownSpec{Duration: time.Minute, Scope: CooldownScopeUser}
+	ctx := testCtx("u1", "c1", "g1")
+
+	if ok, _ := c.checkCooldown("ping", ctx); !ok {
+		t.Fatal("expected first invocation to be allowed")
+	}
+	if ok, remaining := c.checkCooldown("ping", ctx); ok || remaining <= 0 {
+		t.Fatalf("expected second invocation to be blocked with a positive remaining wait, got ok=%v remaining=%v", ok, remaining)
+	}
+}
+
+func TestCheckCooldownIsScopedPerUser(t *testing.T) {
+	c := newTestRouter()
+	c.cooldownConfig["ping"] = cooldownSpec{Duration: time.Minute, Scope: CooldownScopeUser}
+
+	if ok, _ := c.checkCooldown("ping", testCtx("u1", "c1", "g1")); !ok {
+		t.Fatal("expected u1's first invocation to be allowed")
+	}
+	if ok, _ := c.checkCooldown("ping", testCtx("u2", "c1", "g1")); !ok {
+		t.Fatal("expected u2's invocation to be unaffected by u1's cooldown")
+	}
+}
+
+func TestCheckCooldownNoConfigAlwaysAllowed(t *testing.T) {
+	c := newTestRouter()
+	ctx := testCtx("u1", "c1", "g1")
+	for i := 0; i < 3; i++ {
+		if ok, _ := c.checkCooldown("unconfigured", ctx); !ok {
+			t.Fatalf("expected invocation %d to be allowed with no cooldown configured", i)
+		}
+	}
+}
+
+func TestSweepCooldownsLockedEvictsExpiredEntries(t *testing.T) {
+	c := newTestRouter()
+	c.cooldownConfig["ping"] = cooldownSpec{Duration: time.Millisecond, Scope: CooldownScopeUser}
+
+	key := cooldownKey{command: "ping", scope: CooldownScopeUser, id: "u1"}
+	c.cooldowns[key] = time.Now().Add(-time.Hour)
+
+	c.cooldownMu.Lock()
+	c.cooldownChecks = cooldownSweepInterval - 1
+	c.sweepCooldownsLocked()
+	c.cooldownMu.Unlock()
+
+	if _, ok := c.cooldowns[key]; ok {
+		t.Fatal("expected sweepCooldownsLocked to evict the expired entry")
+	}
+}
+
+func TestCooldownScopeID(t *testing.T) {
+	ctx := testCtx("u1", "c1", "g1")
+
+	cases := []struct {
+		scope CooldownScope
+		want  string
+	}{
+		{CooldownScopeUser, "u1"},
+		{CooldownScopeChannel, "c1"},
+		{CooldownScopeGuild, "g1"},
+		{CooldownScopeGlobal, ""},
+	}
+	for _, tc := range cases {
+		if got := cooldownScopeID(tc.scope, ctx); got != tc.want {
+			t.Errorf("cooldownScopeID(%v) = %q, want %q", tc.scope, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Reaction emoji used by Ctx's success/error/warning helpers.
+const (
+	emojiSuccess = "✅"
+	emojiError   = "❌"
+	emojiWarning = "⚠️"
+)
+
+// Ctx wraps a Discord session and the message or interaction that
+// triggered a command, exposing parsed arguments and convenience reply
+// helpers so handlers don't have to re-split content, repeat
+// ChannelMessageSend boilerplate, or branch on how they were invoked.
+type Ctx struct {
+	Session *discordgo.Session
+
+	// Message is set for commands triggered by a prefixed chat message; nil
+	// for interactions. Interaction is the converse.
+	Message     *discordgo.MessageCreate
+	Interaction *discordgo.InteractionCreate
+
+	// Args is the message content split on whitespace after the command
+	// trigger has been stripped. RawArgs is the same content, unsplit. For
+	// RegisterCommandEx specs only the prefix is stripped, so both still
+	// include the matched trigger. Both are empty for interactions; use
+	// Interaction.ApplicationCommandData() for slash command options.
+	Args    []string
+	RawArgs string
+
+	// Groups holds regex capture groups for specs registered with
+	// CommandTypeRegex; nil for every other command.
+	Groups []string
+
+	responded bool
+}
+
+// HandlerFunc handles a command invocation. A returned error is logged and
+// surfaced to the user as a ❌ reaction plus an error message.
+type HandlerFunc func(*Ctx) error
+
+// IsInteraction reports whether ctx was built from a slash command / other
+// interaction rather than a prefixed chat message.
+func (ctx *Ctx) IsInteraction() bool {
+	return ctx.Interaction != nil
+}
+
+// GuildID returns the guild the command was invoked in.
+func (ctx *Ctx) GuildID() string {
+	if ctx.IsInteraction() {
+		return ctx.Interaction.GuildID
+	}
+	return ctx.Message.GuildID
+}
+
+// ChannelID returns the channel the command was invoked in.
+func (ctx *Ctx) ChannelID() string {
+	if ctx.IsInteraction() {
+		return ctx.Interaction.ChannelID
+	}
+	return ctx.Message.ChannelID
+}
+
+// User returns the invoking user.
+func (ctx *Ctx) User() *discordgo.User {
+	if ctx.IsInteraction() {
+		if ctx.Interaction.Member != nil {
+			return ctx.Interaction.Member.User
+		}
+		return ctx.Interaction.User
+	}
+	return ctx.Message.Author
+}
+
+// Guild returns the guild the command was invoked in.
+func (ctx *Ctx) Guild() (*discordgo.Guild, error) {
+	return ctx.Session.State.Guild(ctx.GuildID())
+}
+
+// Member returns the invoking member, falling back to a live API call when
+// it isn't already available (interactions carry it inline).
+func (ctx *Ctx) Member() (*discordgo.Member, error) {
+	if ctx.IsInteraction() && ctx.Interaction.Member != nil {
+		return ctx.Interaction.Member, nil
+	}
+	guildID, userID := ctx.GuildID(), ctx.User().ID
+	member, err := ctx.Session.State.Member(guildID, userID)
+	if err != nil {
+		return ctx.Session.GuildMember(guildID, userID)
+	}
+	return member, nil
+}
+
+// Reply sends content to the channel the command was invoked in. For
+// interactions, the first Reply/ReplyEmbed acknowledges the interaction via
+// InteractionRespond; subsequent calls send followup messages.
+func (ctx *Ctx) Reply(content string) error {
+	if ctx.IsInteraction() {
+		return ctx.respondInteraction(content, nil)
+	}
+	_, err := ctx.Session.ChannelMessageSend(ctx.Message.ChannelID, content)
+	return err
+}
+
+// Replyf formats content with fmt.Sprintf and sends it via Reply.
+func (ctx *Ctx) Replyf(format string, a ...interface{}) error {
+	return ctx.Reply(fmt.Sprintf(format, a...))
+}
+
+// ReplyEmbed sends embed to the channel the command was invoked in, or as
+// an interaction response/followup; see Reply.
+func (ctx *Ctx) ReplyEmbed(embed *discordgo.MessageEmbed) error {
+	if ctx.IsInteraction() {
+		return ctx.respondInteraction("", embed)
+	}
+	_, err := ctx.Session.ChannelMessageSendEmbed(ctx.Message.ChannelID, embed)
+	return err
+}
+
+func (ctx *Ctx) respondInteraction(content string, embed *discordgo.MessageEmbed) error {
+	data := &discordgo.InteractionResponseData{}
+	if content != "" {
+		data.Content = content
+	}
+	if embed != nil {
+		data.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+
+	if !ctx.responded {
+		err := ctx.Session.InteractionRespond(ctx.Interaction.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		})
+		if err == nil {
+			ctx.responded = true
+		}
+		return err
+	}
+
+	_, err := ctx.Session.FollowupMessageCreate(ctx.Interaction.Interaction, true, &discordgo.WebhookParams{
+		Content: data.Content,
+		Embeds:  data.Embeds,
+	})
+	return err
+}
+
+// React adds emoji as a reaction to the triggering message. It's a no-op
+// for interactions, which have no message of their own to react to until
+// their response is fetched back.
+func (ctx *Ctx) React(emoji string) error {
+	if ctx.IsInteraction() {
+		return nil
+	}
+	return ctx.Session.MessageReactionAdd(ctx.Message.ChannelID, ctx.Message.ID, emoji)
+}
+
+// ReactSuccess reacts with ✅.
+func (ctx *Ctx) ReactSuccess() error {
+	return ctx.React(emojiSuccess)
+}
+
+// ReactError reacts with ❌.
+func (ctx *Ctx) ReactError() error {
+	return ctx.React(emojiError)
+}
+
+// ReactWarning reacts with ⚠️.
+func (ctx *Ctx) ReactWarning() error {
+	return ctx.React(emojiWarning)
+}
+
+// newCtx builds a Ctx from the content remaining on m.Content: prefix and
+// command trigger stripped for fast-path commands, only the prefix stripped
+// for RegisterCommandEx specs.
+func newCtx(s *discordgo.Session, m *discordgo.MessageCreate, groups []string) *Ctx {
+	raw := m.Content
+	var args []string
+	if raw != "" {
+		args = strings.Fields(raw)
+	}
+	return &Ctx{
+		Session: s,
+		Message: m,
+		Args:    args,
+		RawArgs: raw,
+		Groups:  groups,
+	}
+}
+
+// newInteractionCtx builds a Ctx for a slash command / other interaction.
+func newInteractionCtx(s *discordgo.Session, i *discordgo.InteractionCreate) *Ctx {
+	return &Ctx{
+		Session:     s,
+		Interaction: i,
+	}
+}
+
+// adaptMessageHandler wraps the legacy MessageHandler signature as a
+// HandlerFunc for RegisterCommand's backwards-compatible shim.
+func adaptMessageHandler(f MessageHandler) HandlerFunc {
+	return func(ctx *Ctx) error {
+		f(ctx.Session, ctx.Message)
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func newMessageCtx(userID, channelID, guildID, content string) *Ctx {
+	return newCtx(&discordgo.Session{}, &discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			Author:    &discordgo.User{ID: userID},
+			ChannelID: channelID,
+			GuildID:   guildID,
+			Content:   content,
+		},
+	}, nil)
+}
+
+func newTestInteractionCtx(s *discordgo.Session, userID, channelID, guildID string) *Ctx {
+	return newInteractionCtx(s, &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			GuildID:   guildID,
+			ChannelID: channelID,
+			Member: &discordgo.Member{
+				User: &discordgo.User{ID: userID},
+			},
+		},
+	})
+}
+
+func TestCtxAccessorsForMessage(t *testing.T) {
+	ctx := newMessageCtx("u1", "c1", "g1", "")
+	if ctx.IsInteraction() {
+		t.Error("expected a message ctx to report IsInteraction() == false")
+	}
+	if got := ctx.GuildID(); got != "g1" {
+		t.Errorf("GuildID() = %q, want %q", got, "g1")
+	}
+	if got := ctx.ChannelID(); got != "c1" {
+		t.Errorf("ChannelID() = %q, want %q", got, "c1")
+	}
+	if got := ctx.User().ID; got != "u1" {
+		t.Errorf("User().ID = %q, want %q", got, "u1")
+	}
+}
+
+func TestCtxAccessorsForInteraction(t *testing.T) {
+	ctx := newTestInteractionCtx(&discordgo.Session{}, "u1", "c1", "g1")
+	if !ctx.IsInteraction() {
+		t.Error("expected an interaction ctx to report IsInteraction() == true")
+	}
+	if got := ctx.GuildID(); got != "g1" {
+		t.Errorf("GuildID() = %q, want %q", got, "g1")
+	}
+	if got := ctx.ChannelID(); got != "c1" {
+		t.Errorf("ChannelID() = %q, want %q", got, "c1")
+	}
+	if got := ctx.User().ID; got != "u1" {
+		t.Errorf("User().ID = %q, want %q", got, "u1")
+	}
+}
+
+func TestCtxMemberUsesInlineInteractionMember(t *testing.T) {
+	ctx := newTestInteractionCtx(&discordgo.Session{}, "u1", "c1", "g1")
+	member, err := ctx.Member()
+	if err != nil {
+		t.Fatalf("Member: %v", err)
+	}
+	if member.User.ID != "u1" {
+		t.Errorf("Member().User.ID = %q, want %q", member.User.ID, "u1")
+	}
+}
+
+func TestNewCtxParsesArgs(t *testing.T) {
+	ctx := newMessageCtx("u1", "c1", "g1", "foo bar  baz")
+	if ctx.RawArgs != "foo bar  baz" {
+		t.Errorf("RawArgs = %q, want %q", ctx.RawArgs, "foo bar  baz")
+	}
+	want := []string{"foo", "bar", "baz"}
+	if len(ctx.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", ctx.Args, want)
+	}
+	for i := range want {
+		if ctx.Args[i] != want[i] {
+			t.Errorf("Args[%d] = %q, want %q", i, ctx.Args[i], want[i])
+		}
+	}
+}
+
+func TestNewCtxEmptyContentHasNoArgs(t *testing.T) {
+	ctx := newMessageCtx("u1", "c1", "g1", "")
+	if ctx.Args != nil {
+		t.Errorf("expected nil Args for empty content, got %v", ctx.Args)
+	}
+}
+
+// respondingSession builds a *discordgo.Session wired up to hit a test
+// server instead of Discord, by overriding discordgo's package-level
+// interaction endpoint funcs. Restores them via t.Cleanup.
+func respondingSession(t *testing.T, status int) *discordgo.Session {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+
+	prevResponse := discordgo.EndpointInteractionResponse
+	prevFollowup := discordgo.EndpointFollowupMessage
+	discordgo.EndpointInteractionResponse = func(iID, iToken string) string { return server.URL }
+	discordgo.EndpointFollowupMessage = func(aID, iToken string) string { return server.URL }
+	t.Cleanup(func() {
+		discordgo.EndpointInteractionResponse = prevResponse
+		discordgo.EndpointFollowupMessage = prevFollowup
+	})
+
+	return &discordgo.Session{
+		Client:      server.Client(),
+		Ratelimiter: discordgo.NewRatelimiter(),
+	}
+}
+
+func TestRespondInteractionMarksRespondedOnlyOnSuccess(t *testing.T) {
+	s := respondingSession(t, http.StatusOK)
+	ctx := newTestInteractionCtx(s, "u1", "c1", "g1")
+
+	if err := ctx.Reply("hello"); err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+	if !ctx.responded {
+		t.Error("expected responded to be true after a successful InteractionRespond")
+	}
+}
+
+func TestRespondInteractionLeavesRespondedFalseOnFailure(t *testing.T) {
+	s := respondingSession(t, http.StatusInternalServerError)
+	ctx := newTestInteractionCtx(s, "u1", "c1", "g1")
+
+	if err := ctx.Reply("hello"); err == nil {
+		t.Fatal("expected Reply to return an error when InteractionRespond fails")
+	}
+	if ctx.responded {
+		t.Error("expected responded to stay false after a failed InteractionRespond, so a retry goes through InteractionRespond again")
+	}
+}
@@ -0,0 +1,207 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord hard limits we paginate the help embeds around.
+const (
+	maxEmbedFields    = 25
+	maxEmbedFieldChar = 1024
+)
+
+// CommandInfo fully describes a command: its help text, how it's
+// categorized and documented, its aliases, and its handler. It's the
+// canonical registration shape RegisterCommand/RegisterCommandFunc/
+// RegisterCommandWithPolicy build on top of.
+type CommandInfo struct {
+	Command       string
+	Help          string
+	Category      string
+	Usage         string
+	Examples      []string
+	Aliases       []string
+	Policy        CommandPolicy
+	Cooldown      time.Duration
+	CooldownScope CooldownScope
+	Handler       HandlerFunc
+}
+
+// RegisterCommandInfo adds a fully-specified command to the router,
+// including its category, usage, examples, aliases and policy. The help
+// command is reserved.
+func (c *CommandRouter) RegisterCommandInfo(info CommandInfo) error {
+	if _, ok := c.commands[info.Command]; ok {
+		return fmt.Errorf("Command %s is already registered", info.Command)
+	}
+	if _, ok := c.helpText[info.Command]; ok {
+		return fmt.Errorf("Help for command %s is already registered", info.Command)
+	}
+	for _, alias := range info.Aliases {
+		if _, ok := c.commands[alias]; ok {
+			return fmt.Errorf("Alias %s collides with a registered command", alias)
+		}
+		if _, ok := c.aliases[alias]; ok {
+			return fmt.Errorf("Alias %s is already registered", alias)
+		}
+	}
+
+	c.commands[info.Command] = info.Handler
+	c.helpText[info.Command] = info.Help
+	c.commandInfo[info.Command] = info
+	c.policies[info.Command] = info.Policy
+	c.cooldownConfig[info.Command] = cooldownSpec{Duration: info.Cooldown, Scope: info.CooldownScope}
+	for _, alias := range info.Aliases {
+		c.aliases[alias] = info.Command
+	}
+	return nil
+}
+
+// help lists every registered command grouped by category, or prints a
+// detailed usage embed for a single command when invoked as "!help <command>".
+func (c *CommandRouter) help(ctx *Ctx) error {
+	if len(ctx.Args) > 0 {
+		return c.helpDetail(ctx, ctx.Args[0])
+	}
+	return c.helpOverview(ctx)
+}
+
+func (c *CommandRouter) helpOverview(ctx *Ctx) error {
+	byCategory := make(map[string][]string)
+	for command, info := range c.commandInfo {
+		category := info.Category
+		if category == "" {
+			category = "Uncategorized"
+		}
+		byCategory[category] = append(byCategory[category], fmt.Sprintf("**%s** - %s", command, c.helpText[command]))
+	}
+
+	var categories []string
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var fields []*discordgo.MessageEmbedField
+	for _, category := range categories {
+		lines := byCategory[category]
+		sort.Strings(lines)
+		for _, chunk := range chunkLines(lines, maxEmbedFieldChar) {
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name:  category,
+				Value: chunk,
+			})
+		}
+	}
+
+	for _, page := range chunkFields(fields, maxEmbedFields) {
+		embed := &discordgo.MessageEmbed{
+			Title:       "Commands",
+			Description: fmt.Sprintf("Use `%shelp <command>` for detailed usage", c.prefixFor(ctx.GuildID())),
+			Fields:      page,
+		}
+		if err := ctx.ReplyEmbed(embed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CommandRouter) helpDetail(ctx *Ctx, command string) error {
+	if canonical, ok := c.aliases[command]; ok {
+		command = canonical
+	}
+	info, ok := c.commandInfo[command]
+	if !ok {
+		return ctx.Replyf("No such command: %s", command)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       command,
+		Description: info.Help,
+	}
+	if info.Usage != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Usage", Value: info.Usage})
+	}
+	if len(info.Examples) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Examples", Value: strings.Join(info.Examples, "\n")})
+	}
+	if len(info.Aliases) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Aliases", Value: strings.Join(info.Aliases, ", ")})
+	}
+	if perms := permissionSummary(info.Policy); perms != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Required permissions", Value: perms})
+	}
+
+	return ctx.ReplyEmbed(embed)
+}
+
+func permissionSummary(policy CommandPolicy) string {
+	var parts []string
+	if policy.OwnerOnly {
+		parts = append(parts, "bot owner only")
+	}
+	if policy.RequireDM {
+		parts = append(parts, "DM only")
+	}
+	if policy.RequiredPerms != 0 {
+		parts = append(parts, fmt.Sprintf("permission bits %d", policy.RequiredPerms))
+	}
+	if len(policy.AllowedRoles) > 0 {
+		parts = append(parts, fmt.Sprintf("roles: %s", strings.Join(policy.AllowedRoles, ", ")))
+	}
+	if len(policy.AllowedChannels) > 0 {
+		parts = append(parts, fmt.Sprintf("channels: %s", strings.Join(policy.AllowedChannels, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (c *CommandRouter) prefixFor(guildID string) string {
+	if server, ok := c.Servers[guildID]; ok && server.Prefix != "" {
+		return server.Prefix
+	}
+	return c.DefaultPrefix
+}
+
+// chunkLines joins lines with newlines, splitting into multiple chunks so
+// none exceeds maxLen (Discord's embed field value limit). A single line
+// longer than maxLen is not split; it becomes a chunk of its own.
+func chunkLines(lines []string, maxLen int) []string {
+	var chunks []string
+	var current string
+	for _, line := range lines {
+		candidate := line
+		if current != "" {
+			candidate = current + "\n" + line
+		}
+		if len(candidate) > maxLen && current != "" {
+			chunks = append(chunks, current)
+			current = line
+			continue
+		}
+		current = candidate
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// chunkFields splits fields into pages of at most maxLen fields each, for
+// Discord's 25-fields-per-embed limit.
+func chunkFields(fields []*discordgo.MessageEmbedField, maxLen int) [][]*discordgo.MessageEmbedField {
+	var pages [][]*discordgo.MessageEmbedField
+	for len(fields) > maxLen {
+		pages = append(pages, fields[:maxLen])
+		fields = fields[maxLen:]
+	}
+	if len(fields) > 0 {
+		pages = append(pages, fields)
+	}
+	return pages
+}
@@ -0,0 +1,63 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestChunkLines(t *testing.T) {
+	chunks := chunkLines([]string{"one", "two", "three"}, 1024)
+	if len(chunks) != 1 || chunks[0] != "one\ntwo\nthree" {
+		t.Errorf("expected a single joined chunk, got %v", chunks)
+	}
+
+	chunks = chunkLines([]string{"aaaa", "bbbb", "cccc"}, 9)
+	want := []string{"aaaa\nbbbb", "cccc"}
+	if len(chunks) != len(want) {
+		t.Fatalf("chunkLines = %v, want %v", chunks, want)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunkLines[%d] = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+}
+
+func TestChunkLinesOversizedSingleLine(t *testing.T) {
+	long := strings.Repeat("a", 20)
+	chunks := chunkLines([]string{long}, 10)
+	if len(chunks) != 1 || chunks[0] != long {
+		t.Errorf("expected a single oversized line to pass through as its own chunk, got %v", chunks)
+	}
+}
+
+func TestChunkLinesEmpty(t *testing.T) {
+	if chunks := chunkLines(nil, 1024); chunks != nil {
+		t.Errorf("expected no chunks for empty input, got %v", chunks)
+	}
+}
+
+func TestChunkFields(t *testing.T) {
+	fields := make([]*discordgo.MessageEmbedField, 30)
+	for i := range fields {
+		fields[i] = &discordgo.MessageEmbedField{Name: "field"}
+	}
+
+	pages := chunkFields(fields, 25)
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	if len(pages[0]) != 25 || len(pages[1]) != 5 {
+		t.Errorf("expected pages of 25 and 5 fields, got %d and %d", len(pages[0]), len(pages[1]))
+	}
+}
+
+func TestChunkFieldsUnderLimit(t *testing.T) {
+	fields := []*discordgo.MessageEmbedField{{Name: "a"}, {Name: "b"}}
+	pages := chunkFields(fields, 25)
+	if len(pages) != 1 || len(pages[0]) != 2 {
+		t.Errorf("expected a single page with both fields, got %v", pages)
+	}
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// InteractionHandler handles a slash command / other interaction. It's an
+// alias for HandlerFunc so a CommandSpec's Handler can serve both message
+// commands and interactions through the shared Ctx abstraction.
+type InteractionHandler = HandlerFunc
+
+// RegisterSlashCommand registers appCmd as a slash command alongside the
+// router's prefix commands. appCmd.GuildID scopes the command to a single
+// guild; left empty, it's registered globally. Call SyncSlashCommands on
+// startup to reconcile the registered set with what Discord has on file.
+func (c *CommandRouter) RegisterSlashCommand(appCmd *discordgo.ApplicationCommand, handler InteractionHandler) error {
+	if _, ok := c.slashHandlers[appCmd.Name]; ok {
+		return fmt.Errorf("Slash command %s is already registered", appCmd.Name)
+	}
+	c.slashHandlers[appCmd.Name] = handler
+	c.slashCommands = append(c.slashCommands, appCmd)
+	return nil
+}
+
+// SyncSlashCommands diffs the router's registered slash commands against
+// what Discord reports via ApplicationCommands for appID, and issues
+// create/update/delete calls so Discord matches the registered set.
+// Commands are grouped by GuildID, so global and guild-scoped commands are
+// reconciled independently. Only scopes with at least one registered
+// command are listed; commands Discord holds in any other guild, or
+// globally when no global command is registered, are left untouched.
+func (c *CommandRouter) SyncSlashCommands(s *discordgo.Session, appID string) error {
+	byGuild := make(map[string][]*discordgo.ApplicationCommand)
+	for _, cmd := range c.slashCommands {
+		byGuild[cmd.GuildID] = append(byGuild[cmd.GuildID], cmd)
+	}
+
+	for guildID, desired := range byGuild {
+		existing, err := s.ApplicationCommands(appID, guildID)
+		if err != nil {
+			return fmt.Errorf("listing existing slash commands for guild %q: %w", guildID, err)
+		}
+
+		existingByName := make(map[string]*discordgo.ApplicationCommand, len(existing))
+		for _, cmd := range existing {
+			existingByName[cmd.Name] = cmd
+		}
+
+		desiredByName := make(map[string]*discordgo.ApplicationCommand, len(desired))
+		for _, cmd := range desired {
+			desiredByName[cmd.Name] = cmd
+		}
+
+		for name, cmd := range desiredByName {
+			current, ok := existingByName[name]
+			if !ok {
+				if _, err := s.ApplicationCommandCreate(appID, guildID, cmd); err != nil {
+					return fmt.Errorf("creating slash command %s: %w", name, err)
+				}
+				continue
+			}
+			if slashCommandChanged(current, cmd) {
+				if _, err := s.ApplicationCommandEdit(appID, guildID, current.ID, cmd); err != nil {
+					return fmt.Errorf("updating slash command %s: %w", name, err)
+				}
+			}
+		}
+
+		for name, cmd := range existingByName {
+			if _, ok := desiredByName[name]; !ok {
+				if err := s.ApplicationCommandDelete(appID, guildID, cmd.ID); err != nil {
+					return fmt.Errorf("deleting slash command %s: %w", name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func slashCommandChanged(current, desired *discordgo.ApplicationCommand) bool {
+	return current.Description != desired.Description ||
+		current.Type != desired.Type ||
+		!reflect.DeepEqual(current.Options, desired.Options)
+}
+
+// HandleInteraction dispatches slash command interactions to their
+// registered handler. It's suitable for discordgo.Session.AddHandler
+// alongside HandleCommand, and enforces the same CommandPolicy/cooldown
+// checks HandleCommand runs on the prefix-message path — a CommandSpec
+// registered with EnableSlash gets identical ACL and cooldown semantics on
+// both paths.
+func (c *CommandRouter) HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	handler, ok := c.slashHandlers[data.Name]
+	if !ok {
+		log.Debugf("No handler registered for slash command: %s", data.Name)
+		return
+	}
+
+	ctx := newInteractionCtx(s, i)
+
+	if !c.checkPolicyFor(ctx, data.Name, c.policies[data.Name]) {
+		log.Debugf("Refusing %s for %s: policy check failed", data.Name, ctx.User().ID)
+		return
+	}
+	if ok, remaining := c.checkCooldown(data.Name, ctx); !ok {
+		ctx.Reply(cooldownMessage(remaining))
+		return
+	}
+
+	c.dispatch(data.Name, ctx, handler)
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestSlashCommandChangedDescription(t *testing.T) {
+	current := &discordgo.ApplicationCommand{Description: "old"}
+	desired := &discordgo.ApplicationCommand{Description: "new"}
+	if !slashCommandChanged(current, desired) {
+		t.Error("expected a description change to be detected")
+	}
+}
+
+func TestSlashCommandChangedType(t *testing.T) {
+	current := &discordgo.ApplicationCommand{Type: discordgo.ChatApplicationCommand}
+	desired := &discordgo.ApplicationCommand{Type: discordgo.UserApplicationCommand}
+	if !slashCommandChanged(current, desired) {
+		t.Error("expected a type change to be detected")
+	}
+}
+
+func TestSlashCommandChangedOptions(t *testing.T) {
+	current := &discordgo.ApplicationCommand{
+		Options: []*discordgo.ApplicationCommandOption{{Name: "arg", Type: discordgo.ApplicationCommandOptionString}},
+	}
+	desired := &discordgo.ApplicationCommand{
+		Options: []*discordgo.ApplicationCommandOption{{Name: "arg", Type: discordgo.ApplicationCommandOptionInteger}},
+	}
+	if !slashCommandChanged(current, desired) {
+		t.Error("expected an option change to be detected")
+	}
+}
+
+func TestSlashCommandUnchanged(t *testing.T) {
+	current := &discordgo.ApplicationCommand{
+		Description: "same",
+		Options:     []*discordgo.ApplicationCommandOption{{Name: "arg", Type: discordgo.ApplicationCommandOptionString}},
+	}
+	desired := &discordgo.ApplicationCommand{
+		Description: "same",
+		Options:     []*discordgo.ApplicationCommandOption{{Name: "arg", Type: discordgo.ApplicationCommandOptionString}},
+	}
+	if slashCommandChanged(current, desired) {
+		t.Error("expected identical commands to report no change")
+	}
+}
@@ -0,0 +1,118 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandType controls how a CommandSpec's Trigger is matched against
+// message content.
+type CommandType int
+
+const (
+	// CommandTypePrefix matches when content starts with Trigger.
+	CommandTypePrefix CommandType = iota
+	// CommandTypeFullMatch matches when content is exactly equal to Trigger.
+	CommandTypeFullMatch
+	// CommandTypeContains matches when content contains Trigger anywhere.
+	CommandTypeContains
+	// CommandTypeRegex matches when Regex matches content, exposing capture
+	// groups to the handler via Ctx.Groups.
+	CommandTypeRegex
+)
+
+// CommandSpec describes a command registered via RegisterCommandEx. The
+// handler receives a Ctx whose Groups field carries the regexp capture
+// groups (as returned by regexp.FindStringSubmatch) for CommandTypeRegex
+// specs, and is nil otherwise.
+type CommandSpec struct {
+	Trigger       string
+	Regex         *regexp.Regexp
+	Type          CommandType
+	Help          string
+	Handler       HandlerFunc
+	Policy        CommandPolicy
+	Cooldown      time.Duration
+	CooldownScope CooldownScope
+
+	// EnableSlash additionally registers this spec as a slash command named
+	// Trigger, served by the same Handler via RegisterSlashCommand. Only
+	// supported for CommandTypeFullMatch specs, since slash command names
+	// are literal, not patterns.
+	EnableSlash bool
+}
+
+// label identifies the spec for policy refusal messages and help listings.
+func (spec CommandSpec) label() string {
+	if spec.Type == CommandTypeRegex && spec.Regex != nil {
+		return spec.Regex.String()
+	}
+	return spec.Trigger
+}
+
+// match reports whether content matches spec, returning regex capture
+// groups when spec.Type is CommandTypeRegex.
+func (spec CommandSpec) match(content string) (bool, []string) {
+	switch spec.Type {
+	case CommandTypePrefix:
+		return strings.HasPrefix(content, spec.Trigger), nil
+	case CommandTypeFullMatch:
+		return content == spec.Trigger, nil
+	case CommandTypeContains:
+		return strings.Contains(content, spec.Trigger), nil
+	case CommandTypeRegex:
+		if spec.Regex == nil {
+			return false, nil
+		}
+		groups := spec.Regex.FindStringSubmatch(content)
+		if groups == nil {
+			return false, nil
+		}
+		return true, groups
+	default:
+		return false, nil
+	}
+}
+
+// RegisterCommandEx registers a CommandSpec matched with a CommandType other
+// than the router's fast-path exact-match map. HandleCommand only consults
+// these specs, in registration order, once the fast-path map misses.
+func (c *CommandRouter) RegisterCommandEx(spec CommandSpec) error {
+	if spec.Type == CommandTypeRegex && spec.Regex == nil {
+		return fmt.Errorf("CommandTypeRegex spec requires a compiled Regex")
+	}
+	if spec.Type != CommandTypeRegex && spec.Trigger == "" {
+		return fmt.Errorf("CommandSpec requires a Trigger")
+	}
+	if spec.EnableSlash && spec.Type != CommandTypeFullMatch {
+		return fmt.Errorf("EnableSlash is only supported for CommandTypeFullMatch specs")
+	}
+	label := spec.label()
+	if spec.Help != "" {
+		c.helpText[label] = spec.Help
+		c.commandInfo[label] = CommandInfo{
+			Command:  label,
+			Help:     spec.Help,
+			Category: "Pattern-matched",
+			Policy:   spec.Policy,
+		}
+	}
+	c.policies[label] = spec.Policy
+	c.cooldownConfig[label] = cooldownSpec{Duration: spec.Cooldown, Scope: spec.CooldownScope}
+	c.exSpecs = append(c.exSpecs, spec)
+
+	if spec.EnableSlash {
+		if err := c.RegisterSlashCommand(&discordgo.ApplicationCommand{
+			Name:        spec.Trigger,
+			Description: spec.Help,
+		}, spec.Handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
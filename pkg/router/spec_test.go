@@ -0,0 +1,70 @@
+package router
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCommandSpecMatchPrefix(t *testing.T) {
+	spec := CommandSpec{Type: CommandTypePrefix, Trigger: "play "}
+	if matched, _ := spec.match("play a song"); !matched {
+		t.Error("expected a prefix match")
+	}
+	if matched, _ := spec.match("pause"); matched {
+		t.Error("expected no match for unrelated content")
+	}
+}
+
+func TestCommandSpecMatchFullMatch(t *testing.T) {
+	spec := CommandSpec{Type: CommandTypeFullMatch, Trigger: "ping"}
+	if matched, _ := spec.match("ping"); !matched {
+		t.Error("expected an exact match")
+	}
+	if matched, _ := spec.match("ping pong"); matched {
+		t.Error("expected no match for trailing content")
+	}
+}
+
+func TestCommandSpecMatchContains(t *testing.T) {
+	spec := CommandSpec{Type: CommandTypeContains, Trigger: "good bot"}
+	if matched, _ := spec.match("you're such a good bot"); !matched {
+		t.Error("expected a substring match")
+	}
+	if matched, _ := spec.match("bad bot"); matched {
+		t.Error("expected no match")
+	}
+}
+
+func TestCommandSpecMatchRegex(t *testing.T) {
+	spec := CommandSpec{Type: CommandTypeRegex, Regex: regexp.MustCompile(`^remind (\w+) (.+)$`)}
+	matched, groups := spec.match("remind me take out the trash")
+	if !matched {
+		t.Fatal("expected a regex match")
+	}
+	if len(groups) != 3 || groups[1] != "me" || groups[2] != "take out the trash" {
+		t.Errorf("unexpected capture groups: %v", groups)
+	}
+
+	if matched, _ := spec.match("remind"); matched {
+		t.Error("expected no match for content missing required groups")
+	}
+}
+
+func TestCommandSpecMatchRegexNilRegex(t *testing.T) {
+	spec := CommandSpec{Type: CommandTypeRegex}
+	if matched, groups := spec.match("anything"); matched || groups != nil {
+		t.Errorf("expected no match with a nil Regex, got matched=%v groups=%v", matched, groups)
+	}
+}
+
+func TestCommandSpecLabel(t *testing.T) {
+	prefixSpec := CommandSpec{Type: CommandTypeFullMatch, Trigger: "ping"}
+	if got := prefixSpec.label(); got != "ping" {
+		t.Errorf("label() = %q, want %q", got, "ping")
+	}
+
+	regexSpec := CommandSpec{Type: CommandTypeRegex, Regex: regexp.MustCompile(`^hi$`)}
+	if got := regexSpec.label(); got != "^hi$" {
+		t.Errorf("label() = %q, want %q", got, "^hi$")
+	}
+}
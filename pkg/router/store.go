@@ -0,0 +1,191 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/sdomino/scribble"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SettingsStore persists ServerSettings (prefixes, ACLs, and other
+// per-guild router state). NewCommandRouterWithOptions accepts one so the
+// flat-file scribble layout can be swapped for something that scales past
+// a few thousand guilds.
+type SettingsStore interface {
+	Load(guildID string) (ServerSettings, error)
+	Save(settings ServerSettings) error
+	LoadAll() ([]ServerSettings, error)
+	Delete(guildID string) error
+}
+
+// settingsBucket is the scribble/bolt collection name ServerSettings are
+// stored under, kept from the original scribble-only layout.
+const settingsBucket = "Servers"
+
+// ScribbleStore is the original flat-JSON-per-guild-file SettingsStore
+// implementation, kept for back-compat with existing on-disk data.
+type ScribbleStore struct {
+	db *scribble.Driver
+}
+
+// NewScribbleStore opens (creating if necessary) a scribble store rooted at dir.
+func NewScribbleStore(dir string) (*ScribbleStore, error) {
+	db, err := scribble.New(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ScribbleStore{db: db}, nil
+}
+
+func (s *ScribbleStore) Load(guildID string) (ServerSettings, error) {
+	var settings ServerSettings
+	if err := s.db.Read(settingsBucket, guildID, &settings); err != nil {
+		return ServerSettings{}, err
+	}
+	return settings, nil
+}
+
+func (s *ScribbleStore) Save(settings ServerSettings) error {
+	return s.db.Write(settingsBucket, settings.GuildID, settings)
+}
+
+func (s *ScribbleStore) LoadAll() ([]ServerSettings, error) {
+	raw, err := s.db.ReadAll(settingsBucket)
+	if err != nil {
+		return nil, err
+	}
+	all := make([]ServerSettings, 0, len(raw))
+	for _, r := range raw {
+		var settings ServerSettings
+		if err := json.Unmarshal(r, &settings); err != nil {
+			return nil, err
+		}
+		all = append(all, settings)
+	}
+	return all, nil
+}
+
+func (s *ScribbleStore) Delete(guildID string) error {
+	return s.db.Delete(settingsBucket, guildID)
+}
+
+// MemoryStore is an in-memory SettingsStore, useful for tests.
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string]ServerSettings
+}
+
+// NewMemoryStore returns an empty in-memory SettingsStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(map[string]ServerSettings)}
+}
+
+func (s *MemoryStore) Load(guildID string) (ServerSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.data[guildID]
+	if !ok {
+		return ServerSettings{}, fmt.Errorf("no settings stored for guild %s", guildID)
+	}
+	return settings, nil
+}
+
+func (s *MemoryStore) Save(settings ServerSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[settings.GuildID] = settings
+	return nil
+}
+
+func (s *MemoryStore) LoadAll() ([]ServerSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]ServerSettings, 0, len(s.data))
+	for _, settings := range s.data {
+		all = append(all, settings)
+	}
+	return all, nil
+}
+
+func (s *MemoryStore) Delete(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, guildID)
+	return nil
+}
+
+// boltBucket is the bbolt bucket ServerSettings are stored under.
+var boltBucket = []byte(settingsBucket)
+
+// BoltStore is a SettingsStore backed by a single-file bbolt database,
+// giving atomic multi-field updates and safe concurrent access without the
+// per-guild file churn of ScribbleStore.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying bbolt database file.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltStore) Load(guildID string) (ServerSettings, error) {
+	var settings ServerSettings
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(guildID))
+		if data == nil {
+			return fmt.Errorf("no settings stored for guild %s", guildID)
+		}
+		return json.Unmarshal(data, &settings)
+	})
+	return settings, err
+}
+
+func (b *BoltStore) Save(settings ServerSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(settings.GuildID), data)
+	})
+}
+
+func (b *BoltStore) LoadAll() ([]ServerSettings, error) {
+	var all []ServerSettings
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(_, v []byte) error {
+			var settings ServerSettings
+			if err := json.Unmarshal(v, &settings); err != nil {
+				return err
+			}
+			all = append(all, settings)
+			return nil
+		})
+	})
+	return all, err
+}
+
+func (b *BoltStore) Delete(guildID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(guildID))
+	})
+}
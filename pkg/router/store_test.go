@@ -0,0 +1,103 @@
+package router
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryStoreRoundTrip(t *testing.T) {
+	store := NewMemoryStore()
+	settings := ServerSettings{GuildID: "g1", Prefix: "!"}
+
+	if err := store.Save(settings); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Load("g1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.GuildID != settings.GuildID || got.Prefix != settings.Prefix {
+		t.Errorf("Load returned %+v, want %+v", got, settings)
+	}
+
+	all, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if len(all) != 1 || all[0].GuildID != settings.GuildID {
+		t.Errorf("LoadAll returned %+v, want [%+v]", all, settings)
+	}
+
+	if err := store.Delete("g1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Load("g1"); err == nil {
+		t.Error("expected Load to error after Delete")
+	}
+}
+
+func TestMemoryStoreLoadMissingGuild(t *testing.T) {
+	store := NewMemoryStore()
+	if _, err := store.Load("missing"); err == nil {
+		t.Error("expected Load to error for a guild that was never saved")
+	}
+}
+
+func TestBoltStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.db")
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore: %v", err)
+	}
+	defer store.Close()
+
+	settings := ServerSettings{
+		GuildID:     "g1",
+		Prefix:      "!",
+		CommandACLs: map[string]CommandACL{"ping": {AllowedChannels: []string{"c1"}}},
+	}
+
+	if err := store.Save(settings); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Load("g1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.GuildID != settings.GuildID || got.Prefix != settings.Prefix {
+		t.Errorf("Load returned %+v, want %+v", got, settings)
+	}
+	if got.CommandACLs["ping"].AllowedChannels[0] != "c1" {
+		t.Errorf("Load lost CommandACLs: %+v", got.CommandACLs)
+	}
+
+	all, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	if len(all) != 1 || all[0].GuildID != "g1" {
+		t.Errorf("LoadAll returned %+v, want one entry for g1", all)
+	}
+
+	if err := store.Delete("g1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Load("g1"); err == nil {
+		t.Error("expected Load to error after Delete")
+	}
+}
+
+func TestBoltStoreLoadMissingGuild(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.db")
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Load("missing"); err == nil {
+		t.Error("expected Load to error for a guild that was never saved")
+	}
+}